@@ -0,0 +1,203 @@
+package tgbotapp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+)
+
+// MaxCallbackDataBytes is the hard limit Telegram enforces on callback_data.
+const MaxCallbackDataBytes = 64
+
+const (
+	callbackDelimiter = ":"
+	longPayloadPrefix = "@"
+)
+
+// splitCallbackData splits a CallbackData-encoded payload into its action
+// and raw remainder (the base64 payload, or a long-payload token prefixed
+// with longPayloadPrefix). The split happens at the last callbackDelimiter
+// rather than the first, since base64.RawURLEncoding payloads never contain
+// one but an action itself may, e.g. NewCallback("cart:remove").
+func splitCallbackData(data string) (action, rest string, ok bool) {
+	i := strings.LastIndex(data, callbackDelimiter)
+	if i < 0 {
+		return "", "", false
+	}
+
+	return data[:i], data[i+len(callbackDelimiter):], true
+}
+
+// callbackDataAction returns the action prefix of a CallbackData-encoded
+// payload. RouterWithDefault uses it to route a callback query to the
+// CallbackHandler registered under cb's action, e.g.
+// router.AddHandler("cart:remove", CallbackHandler, f) for
+// cb := NewCallback("cart:remove").
+func callbackDataAction(data string) (action string, ok bool) {
+	action, _, ok = splitCallbackData(data)
+	return
+}
+
+// CallbackStore persists callback payloads that would otherwise exceed
+// MaxCallbackDataBytes, so only a short token needs to travel over the wire.
+type CallbackStore interface {
+	// Put must return a token that does not itself contain
+	// callbackDelimiter (":"); EncodeCtx appends it directly after the
+	// action and longPayloadPrefix, and splitCallbackData locates the
+	// boundary by the last delimiter, so a token containing one would
+	// corrupt routing and decoding of the resulting callback data.
+	Put(ctx *BotContext, data string) (token string, err error)
+	Get(ctx *BotContext, token string) (data string, err error)
+}
+
+// defaultCallbackStore backs ctx.BindCallback's long-payload lookups, since
+// BindCallback has no CallbackData instance of its own to carry one.
+var defaultCallbackStore CallbackStore
+
+// SetDefaultCallbackStore configures the CallbackStore used to resolve
+// long-payload tokens encoded by any CallbackData's long-payload mode.
+func SetDefaultCallbackStore(store CallbackStore) {
+	defaultCallbackStore = store
+}
+
+// CallbackData encodes and decodes a typed payload for a single callback
+// action, replacing manual `action|arg1|arg2` parsing of ctx.Params.
+type CallbackData struct {
+	action string
+	store  CallbackStore
+}
+
+// NewCallback builds a CallbackData for action. action may contain
+// callbackDelimiter (e.g. "cart:remove"); splitCallbackData splits on the
+// last occurrence, so the encoded payload never confuses the boundary.
+func NewCallback(action string) *CallbackData {
+	return &CallbackData{action: action}
+}
+
+// WithStore opts this CallbackData into long-payload mode: payloads that
+// would exceed MaxCallbackDataBytes are persisted in store, and only a
+// short token is sent as callback_data.
+func (c *CallbackData) WithStore(store CallbackStore) *CallbackData {
+	c.store = store
+	return c
+}
+
+// Encode marshals v and prefixes it with this CallbackData's action. It
+// returns an error if the encoded result exceeds MaxCallbackDataBytes; use
+// EncodeCtx if a CallbackStore has been configured via WithStore.
+func (c *CallbackData) Encode(v any) (string, error) {
+	encoded, err := c.encode(v)
+	if err != nil {
+		return "", err
+	}
+
+	if len(encoded) > MaxCallbackDataBytes {
+		return "", NewErrCallbackPayloadTooLarge(len(encoded), MaxCallbackDataBytes)
+	}
+
+	return encoded, nil
+}
+
+// EncodeCtx behaves like Encode, but falls back to storing the payload in
+// this CallbackData's CallbackStore and sending a short token instead, when
+// the encoded payload would exceed MaxCallbackDataBytes.
+func (c *CallbackData) EncodeCtx(ctx *BotContext, v any) (string, error) {
+	encoded, err := c.encode(v)
+	if err != nil {
+		return "", err
+	}
+
+	if len(encoded) <= MaxCallbackDataBytes {
+		return encoded, nil
+	}
+
+	if c.store == nil {
+		return "", NewErrCallbackPayloadTooLarge(len(encoded), MaxCallbackDataBytes)
+	}
+
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := c.store.Put(ctx, string(payload))
+	if err != nil {
+		return "", err
+	}
+
+	if strings.Contains(token, callbackDelimiter) {
+		return "", NewErrInvalidArgument("CallbackStore token must not contain callbackDelimiter", "token")
+	}
+
+	tokenized := c.action + callbackDelimiter + longPayloadPrefix + token
+	if len(tokenized) > MaxCallbackDataBytes {
+		return "", NewErrCallbackPayloadTooLarge(len(tokenized), MaxCallbackDataBytes)
+	}
+
+	return tokenized, nil
+}
+
+func (c *CallbackData) encode(v any) (string, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return c.action + callbackDelimiter + base64.RawURLEncoding.EncodeToString(payload), nil
+}
+
+// Decode routes data by its action prefix and, on a match, populates out
+// with the decoded payload. It resolves long-payload tokens through this
+// CallbackData's CallbackStore.
+func (c *CallbackData) Decode(ctx *BotContext, data string, out any) error {
+	action, rest, ok := splitCallbackData(data)
+	if !ok || action != c.action {
+		return NewErrCallbackActionMismatch(c.action, action)
+	}
+
+	payload, err := resolveCallbackPayload(ctx, rest, c.store)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(payload, out)
+}
+
+// BindCallback decodes the current update's callback_data into out. The
+// action prefix is assumed to already have been routed on and is ignored;
+// only the payload after the delimiter is decoded. Long-payload tokens are
+// resolved through the store configured via SetDefaultCallbackStore.
+func (ctx *BotContext) BindCallback(out any) error {
+	if ctx.Update.CallbackQuery == nil {
+		return NewErrInvalidArgument("no callback query on this update", "out")
+	}
+
+	_, rest, ok := splitCallbackData(ctx.Update.CallbackQuery.Data)
+	if !ok {
+		return NewErrInvalidArgument("callback data has no encoded payload", "out")
+	}
+
+	payload, err := resolveCallbackPayload(ctx, rest, defaultCallbackStore)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(payload, out)
+}
+
+func resolveCallbackPayload(ctx *BotContext, rest string, store CallbackStore) ([]byte, error) {
+	if !strings.HasPrefix(rest, longPayloadPrefix) {
+		return base64.RawURLEncoding.DecodeString(rest)
+	}
+
+	if store == nil {
+		return nil, NewErrCallbackStoreNotConfigured()
+	}
+
+	raw, err := store.Get(ctx, strings.TrimPrefix(rest, longPayloadPrefix))
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(raw), nil
+}
@@ -0,0 +1,160 @@
+package tgbotapp
+
+import (
+	"strings"
+	"testing"
+)
+
+// fakeCallbackStore is a minimal in-memory CallbackStore for exercising the
+// long-payload path without depending on any concrete BotContext shape.
+type fakeCallbackStore struct {
+	tokens map[string]string
+	next   int
+	token  string
+}
+
+func (s *fakeCallbackStore) Put(_ *BotContext, data string) (string, error) {
+	if s.tokens == nil {
+		s.tokens = make(map[string]string)
+	}
+
+	token := s.token
+	if token == "" {
+		s.next++
+		token = strings.Repeat("t", s.next)
+	}
+
+	s.tokens[token] = data
+	return token, nil
+}
+
+func (s *fakeCallbackStore) Get(_ *BotContext, token string) (string, error) {
+	data, ok := s.tokens[token]
+	if !ok {
+		return "", NewErrInvalidArgument("unknown token", "token")
+	}
+
+	return data, nil
+}
+
+func TestSplitCallbackData(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		wantAction string
+		wantRest   string
+		wantOK     bool
+	}{
+		{name: "simple action", data: "cart:AA", wantAction: "cart", wantRest: "AA", wantOK: true},
+		{name: "action containing delimiter splits at the last one", data: "cart:remove:AA", wantAction: "cart:remove", wantRest: "AA", wantOK: true},
+		{name: "no delimiter", data: "cart", wantOK: false},
+		{name: "empty string", data: "", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action, rest, ok := splitCallbackData(tt.data)
+			if ok != tt.wantOK {
+				t.Fatalf("splitCallbackData(%q) ok = %v, want %v", tt.data, ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+
+			if action != tt.wantAction || rest != tt.wantRest {
+				t.Errorf("splitCallbackData(%q) = (%q, %q), want (%q, %q)", tt.data, action, rest, tt.wantAction, tt.wantRest)
+			}
+		})
+	}
+}
+
+type callbackPayload struct {
+	ItemID int
+	Qty    int
+}
+
+func TestCallbackDataEncodeDecodeRoundTrip(t *testing.T) {
+	cb := NewCallback("cart:remove")
+
+	encoded, err := cb.Encode(callbackPayload{ItemID: 42, Qty: 3})
+	if err != nil {
+		t.Fatalf("Encode returned error: %v", err)
+	}
+
+	var out callbackPayload
+	if err := cb.Decode(nil, encoded, &out); err != nil {
+		t.Fatalf("Decode returned error: %v", err)
+	}
+
+	if out != (callbackPayload{ItemID: 42, Qty: 3}) {
+		t.Errorf("Decode round-trip = %+v, want {42 3}", out)
+	}
+}
+
+func TestCallbackDataDecodeActionMismatch(t *testing.T) {
+	cb := NewCallback("cart:remove")
+
+	other := NewCallback("cart:add")
+	encoded, err := other.Encode(callbackPayload{ItemID: 1})
+	if err != nil {
+		t.Fatalf("Encode returned error: %v", err)
+	}
+
+	var out callbackPayload
+	if err := cb.Decode(nil, encoded, &out); err == nil {
+		t.Fatal("Decode returned nil error for a mismatched action, want one")
+	}
+}
+
+func TestCallbackDataEncodeRejectsOversizePayload(t *testing.T) {
+	cb := NewCallback("cart:remove")
+
+	_, err := cb.Encode(struct{ Name string }{strings.Repeat("x", int(MaxCallbackDataBytes))})
+	if err == nil {
+		t.Fatal("Encode returned nil error for an oversize payload, want one")
+	}
+}
+
+func TestCallbackDataEncodeCtxLongPayload(t *testing.T) {
+	store := &fakeCallbackStore{}
+	cb := NewCallback("cart:remove").WithStore(store)
+
+	longPayload := callbackPayload{ItemID: 1, Qty: 1}
+	longName := strings.Repeat("x", int(MaxCallbackDataBytes))
+
+	encoded, err := cb.EncodeCtx(nil, struct {
+		callbackPayload
+		Name string
+	}{longPayload, longName})
+	if err != nil {
+		t.Fatalf("EncodeCtx returned error: %v", err)
+	}
+
+	if len(encoded) > MaxCallbackDataBytes {
+		t.Fatalf("EncodeCtx returned %d bytes, want <= %d", len(encoded), MaxCallbackDataBytes)
+	}
+
+	var out struct {
+		callbackPayload
+		Name string
+	}
+	if err := cb.Decode(nil, encoded, &out); err != nil {
+		t.Fatalf("Decode returned error: %v", err)
+	}
+
+	if out.Name != longName {
+		t.Errorf("Decode round-trip Name = %q, want %q", out.Name, longName)
+	}
+}
+
+func TestCallbackDataEncodeCtxRejectsTokenContainingDelimiter(t *testing.T) {
+	store := &fakeCallbackStore{token: "bad:token"}
+	cb := NewCallback("cart:remove").WithStore(store)
+
+	longName := strings.Repeat("x", int(MaxCallbackDataBytes))
+
+	_, err := cb.EncodeCtx(nil, struct{ Name string }{longName})
+	if err == nil {
+		t.Fatal("EncodeCtx returned nil error for a token containing callbackDelimiter, want one")
+	}
+}
@@ -0,0 +1,323 @@
+package tgbotapp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// CancelCommand is the built-in command every Conversation watches for,
+// regardless of which step it is currently on.
+const CancelCommand = "cancel"
+
+// ConversationStep is a single step of a Conversation. Prompt sends whatever
+// the user should see for this step. Parse reads the incoming update once
+// the user has replied, populating the conversation's Data bag. Validate is
+// optional and runs before Parse; returning an error re-prompts the step
+// instead of advancing.
+type ConversationStep struct {
+	Prompt   HandlerFunc
+	Parse    func(ctx *BotContext) error
+	Validate func(ctx *BotContext) error
+}
+
+// Conversation is a multi-step wizard flow layered on top of Router and
+// Session: /name triggers the first step, and each reply walks the user
+// through the rest without handler code having to manage Session state by
+// hand.
+type Conversation struct {
+	name  string
+	steps []ConversationStep
+
+	mu   sync.Mutex
+	data map[int64]map[string]any
+}
+
+// conversationRegistry tracks, for a single underlying RouteTable, the
+// conversations registered onto it and whether the shared /cancel handler
+// has been wired up yet. Registries are scoped per-RouteTable, rather than
+// per-Router value, so that Conversations registered through different
+// Group() sub-routers of the same table still share one /cancel handler,
+// while two independent RouteTables (e.g. in separate tests, or separate
+// bots in one process) don't contend over each other's wiring.
+type conversationRegistry struct {
+	mu            sync.Mutex
+	conversations map[string]*Conversation
+	cancelWired   bool
+}
+
+var (
+	registriesMu sync.Mutex
+	registries   = map[*RouteTable]*conversationRegistry{}
+)
+
+// rootTabler is implemented by both *RouteTable (returning itself) and
+// *groupRouter (delegating to the RouteTable its chain of Group calls
+// ultimately wraps), so registryFor can scope a conversationRegistry to the
+// underlying table no matter how many Group layers were used to reach it.
+type rootTabler interface {
+	rootTable() *RouteTable
+}
+
+func registryFor(router Router) *conversationRegistry {
+	table := router.(rootTabler).rootTable()
+
+	registriesMu.Lock()
+	defer registriesMu.Unlock()
+
+	reg, ok := registries[table]
+	if !ok {
+		reg = &conversationRegistry{conversations: make(map[string]*Conversation)}
+		registries[table] = reg
+	}
+
+	return reg
+}
+
+// NewConversation builds a Conversation named name out of steps, walked in
+// order starting from the first.
+func NewConversation(name string, steps ...ConversationStep) *Conversation {
+	return &Conversation{
+		name:  name,
+		steps: steps,
+		data:  make(map[int64]map[string]any),
+	}
+}
+
+// Register wires the conversation's entry command and the synthesized
+// MessageHandler for each step onto router, and installs the shared /cancel
+// command handler the first time any Conversation is registered onto
+// router's underlying RouteTable.
+func (c *Conversation) Register(router Router) error {
+	if err := router.AddHandler(c.name, CommandHandler, c.entry); err != nil {
+		return err
+	}
+
+	for i := range c.steps {
+		step := i
+		if err := router.AddHandler(c.stepState(step), MessageHandler, c.handleStep(step)); err != nil {
+			return err
+		}
+	}
+
+	reg := registryFor(router)
+
+	reg.mu.Lock()
+	reg.conversations[c.name] = c
+	installCancel := !reg.cancelWired
+	reg.cancelWired = true
+	reg.mu.Unlock()
+
+	if installCancel {
+		if err := router.AddHandler(CancelCommand, CommandHandler, cancelHandlerFor(reg)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Conversation) stepState(i int) SessionState {
+	return SessionState(fmt.Sprintf("%s:step%d", c.name, i))
+}
+
+func (c *Conversation) entry(ctx *BotContext) {
+	if len(c.steps) == 0 {
+		return
+	}
+
+	ctx.Session.SetState(c.stepState(0))
+	c.prompt(ctx, 0)
+}
+
+func (c *Conversation) handleStep(i int) HandlerFunc {
+	return func(ctx *BotContext) {
+		// Attach this step's ConversationState before Validate/Parse run, not
+		// just when (re-)prompting, so they can call ctx.Conversation() to
+		// read/write Data or branch the flow with Next/Back/Cancel.
+		state := c.attach(ctx, i)
+
+		step := c.steps[i]
+
+		if step.Validate != nil {
+			if err := step.Validate(ctx); err != nil {
+				if !state.redirected {
+					c.prompt(ctx, i)
+				}
+				return
+			}
+		}
+
+		if state.redirected {
+			return
+		}
+
+		if step.Parse != nil {
+			if err := step.Parse(ctx); err != nil {
+				if !state.redirected {
+					c.prompt(ctx, i)
+				}
+				return
+			}
+		}
+
+		if state.redirected {
+			return
+		}
+
+		next := i + 1
+		if next >= len(c.steps) {
+			c.finish(ctx)
+			return
+		}
+
+		ctx.Session.SetState(c.stepState(next))
+		c.prompt(ctx, next)
+	}
+}
+
+func (c *Conversation) attach(ctx *BotContext, step int) *ConversationState {
+	state := &ConversationState{
+		ctx:  ctx,
+		conv: c,
+		step: step,
+	}
+
+	ctx.Ctx = context.WithValue(ctx.Ctx, conversationCtxKey{}, state)
+
+	return state
+}
+
+func (c *Conversation) prompt(ctx *BotContext, step int) {
+	c.attach(ctx, step)
+	c.steps[step].Prompt(ctx)
+}
+
+func (c *Conversation) finish(ctx *BotContext) {
+	id := sessionChatID(ctx)
+
+	c.mu.Lock()
+	delete(c.data, id)
+	c.mu.Unlock()
+
+	ctx.Session.SetState(SessionState(""))
+}
+
+func (c *Conversation) bag(ctx *BotContext) map[string]any {
+	id := sessionChatID(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	bag, ok := c.data[id]
+	if !ok {
+		bag = make(map[string]any)
+		c.data[id] = bag
+	}
+
+	return bag
+}
+
+type conversationCtxKey struct{}
+
+// ConversationState is the live handle for a running Conversation, available
+// from every step's Prompt/Validate/Parse via ctx.Conversation().
+type ConversationState struct {
+	ctx  *BotContext
+	conv *Conversation
+	step int
+
+	// redirected is set by Next/Back/Cancel so handleStep's own
+	// auto-advance (run after Validate/Parse return nil) knows the step
+	// already moved the flow itself and must not also transition it.
+	redirected bool
+}
+
+// Data returns the conversation's typed bag, shared across all of its
+// steps for as long as the conversation runs.
+func (c *ConversationState) Data() map[string]any {
+	return c.conv.bag(c.ctx)
+}
+
+// Next advances to the following step and sends its prompt. If the current
+// step is the last one, the conversation ends instead.
+func (c *ConversationState) Next() {
+	c.redirected = true
+
+	next := c.step + 1
+	if next >= len(c.conv.steps) {
+		c.conv.finish(c.ctx)
+		return
+	}
+
+	c.ctx.Session.SetState(c.conv.stepState(next))
+	c.conv.prompt(c.ctx, next)
+}
+
+// Back returns to the previous step and re-sends its prompt. Back is a
+// no-op on the first step.
+func (c *ConversationState) Back() {
+	if c.step == 0 {
+		return
+	}
+
+	c.redirected = true
+
+	prev := c.step - 1
+	c.ctx.Session.SetState(c.conv.stepState(prev))
+	c.conv.prompt(c.ctx, prev)
+}
+
+// Cancel aborts the conversation and clears its Session state and Data bag.
+func (c *ConversationState) Cancel() {
+	c.redirected = true
+	c.conv.finish(c.ctx)
+}
+
+// Conversation returns the ConversationState for whichever Conversation step
+// is currently handling ctx, or nil outside of a conversation step.
+func (ctx *BotContext) Conversation() *ConversationState {
+	state, _ := ctx.Ctx.Value(conversationCtxKey{}).(*ConversationState)
+	return state
+}
+
+// cancelHandlerFor builds the shared /cancel CommandHandler installed the
+// first time a Conversation is registered onto reg's Router. It looks up the
+// conversation implied by the session's current state and ends it.
+func cancelHandlerFor(reg *conversationRegistry) HandlerFunc {
+	return func(ctx *BotContext) {
+		if ctx.Session == nil {
+			return
+		}
+
+		state := string(ctx.Session.CurrentState())
+		name, _, ok := strings.Cut(state, ":")
+		if !ok {
+			return
+		}
+
+		reg.mu.Lock()
+		conv, ok := reg.conversations[name]
+		reg.mu.Unlock()
+
+		if !ok {
+			return
+		}
+
+		conv.finish(ctx)
+	}
+}
+
+func sessionChatID(ctx *BotContext) int64 {
+	switch {
+	case ctx.Update.Message != nil:
+		return ctx.Update.Message.Chat.ID
+	case ctx.Update.CallbackQuery != nil && ctx.Update.CallbackQuery.Message != nil:
+		return ctx.Update.CallbackQuery.Message.Chat.ID
+	case ctx.Update.EditedMessage != nil:
+		return ctx.Update.EditedMessage.Chat.ID
+	default:
+		return 0
+	}
+}
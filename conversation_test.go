@@ -0,0 +1,43 @@
+package tgbotapp
+
+import "testing"
+
+func TestRegistryForSharesRegistryAcrossGroups(t *testing.T) {
+	table := NewRouteTable()
+	admin := table.Group("admin")
+	nested := admin.Group("reports")
+
+	regRoot := registryFor(table)
+	regAdmin := registryFor(admin)
+	regNested := registryFor(nested)
+
+	if regRoot != regAdmin || regRoot != regNested {
+		t.Fatal("registryFor returned different registries for the same underlying RouteTable reached through Group layers")
+	}
+}
+
+func TestRegistryForIsolatesIndependentRouteTables(t *testing.T) {
+	a := registryFor(NewRouteTable())
+	b := registryFor(NewRouteTable())
+
+	if a == b {
+		t.Fatal("registryFor returned the same registry for two independent RouteTables")
+	}
+}
+
+func TestConversationRegisterSharesCancelHandlerAcrossGroups(t *testing.T) {
+	table := NewRouteTable()
+
+	first := NewConversation("find_train", ConversationStep{Prompt: func(*BotContext) {}})
+	if err := first.Register(table.Group("trips")); err != nil {
+		t.Fatalf("Register on first group returned error: %v", err)
+	}
+
+	// Before the RouteTable-scoped registry fix, this second Register call
+	// would try to re-add "cancel" on the root table via a distinct
+	// registryFor(Router) entry and fail with ErrHandlerAlreadyExists.
+	second := NewConversation("book_hotel", ConversationStep{Prompt: func(*BotContext) {}})
+	if err := second.Register(table.Group("bookings")); err != nil {
+		t.Fatalf("Register on second group returned error: %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package tgbotapp
+
+// AddHandlerWithMiddleware implements Router.
+func (r *RouteTable) AddHandlerWithMiddleware(name string, handlerType HandlerAction, f HandlerFunc, mws ...Middleware) error {
+	// name is empty exactly when it is SingletonHandlerName, the
+	// conventional key for handler types with no natural name to route on
+	// (inline queries, polls, chat member updates, ...), so it is valid.
+	if name != SingletonHandlerName && len(name) < 1 {
+		return NewErrInvalidArgument("name must not be empty.", "name")
+	}
+
+	if _, ok := r.handlers[handlerType]; !ok {
+		r.handlers[handlerType] = make(map[string]HandlerInfo)
+	}
+
+	if _, ok := r.handlers[handlerType][name]; ok {
+		return NewErrHandlerAlreadyExists(name, handlerType)
+	}
+
+	r.handlers[handlerType][name] = HandlerInfo{
+		Name:        name,
+		Type:        handlerType,
+		Func:        chainMiddleware(mws, f),
+		Middlewares: mws,
+	}
+
+	return nil
+}
+
+// Group implements Router.
+func (r *RouteTable) Group(name string, mws ...Middleware) Router {
+	return &groupRouter{
+		root: r,
+		name: name,
+		mws:  mws,
+	}
+}
+
+// chainMiddleware wraps final so mws run in order, each able to short
+// circuit by not calling next, before final itself runs.
+func chainMiddleware(mws []Middleware, final HandlerFunc) HandlerFunc {
+	h := final
+	for i := len(mws) - 1; i >= 0; i-- {
+		mw := mws[i]
+		next := h
+		h = func(ctx *BotContext) { mw(ctx, next) }
+	}
+	return h
+}
+
+// groupRouter is the Router returned by Group. It delegates lookups to root
+// and applies its own middleware stack, ahead of any per-call middleware, to
+// every handler it registers.
+type groupRouter struct {
+	root Router
+	name string
+	mws  []Middleware
+}
+
+// Name returns this group's fully-qualified name, e.g. "admin/reports" for
+// a group nested under another via Group.
+func (g *groupRouter) Name() string {
+	return g.name
+}
+
+// rootTable delegates down to the underlying RouteTable, however many Group
+// layers were used to reach this router.
+func (g *groupRouter) rootTable() *RouteTable {
+	return g.root.(interface{ rootTable() *RouteTable }).rootTable()
+}
+
+// GetHandler implements Router.
+func (g *groupRouter) GetHandler(name string, handlerType HandlerAction) (*HandlerInfo, bool) {
+	return g.root.GetHandler(name, handlerType)
+}
+
+// AddHandler implements Router.
+func (g *groupRouter) AddHandler(name string, handlerType HandlerAction, f HandlerFunc) error {
+	return g.AddHandlerWithMiddleware(name, handlerType, f)
+}
+
+// AddHandlerWithMiddleware implements Router.
+func (g *groupRouter) AddHandlerWithMiddleware(name string, handlerType HandlerAction, f HandlerFunc, mws ...Middleware) error {
+	return g.root.AddHandlerWithMiddleware(name, handlerType, f, g.chain(mws)...)
+}
+
+// AddPatternHandler implements Router.
+func (g *groupRouter) AddPatternHandler(pattern string, handlerType HandlerAction, f HandlerFunc) error {
+	return g.AddPatternHandlerWithMiddleware(pattern, handlerType, f)
+}
+
+// AddPatternHandlerWithMiddleware implements Router.
+func (g *groupRouter) AddPatternHandlerWithMiddleware(pattern string, handlerType HandlerAction, f HandlerFunc, mws ...Middleware) error {
+	return g.root.AddPatternHandlerWithMiddleware(pattern, handlerType, f, g.chain(mws)...)
+}
+
+// MatchPattern implements Router.
+func (g *groupRouter) MatchPattern(name string, handlerType HandlerAction) (*HandlerInfo, map[string]string, bool) {
+	return g.root.MatchPattern(name, handlerType)
+}
+
+// Group implements Router, nesting mws under this group's own stack.
+func (g *groupRouter) Group(name string, mws ...Middleware) Router {
+	return &groupRouter{
+		root: g.root,
+		name: g.name + "/" + name,
+		mws:  g.chain(mws),
+	}
+}
+
+func (g *groupRouter) chain(mws []Middleware) []Middleware {
+	chained := make([]Middleware, 0, len(g.mws)+len(mws))
+	chained = append(chained, g.mws...)
+	chained = append(chained, mws...)
+	return chained
+}
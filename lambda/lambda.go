@@ -0,0 +1,51 @@
+// Package lambda adapts a tgbotapp.WebhookSource to run behind an AWS
+// Lambda function fronted by API Gateway, as an alternative to the
+// net/http.Handler exposed directly by WebhookSource.
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/textproto"
+
+	"github.com/aws/aws-lambda-go/events"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/xenolink-tech/go-telegram-bot-app"
+)
+
+// Handler returns an AWS Lambda API Gateway proxy handler that decodes the
+// request body into a tgbotapi.Update and delivers it through source.
+//
+//	source := tgbotapp.NewWebhookSource(secret)
+//	go source.Listen(ctx, func(u tgbotapi.Update) { middleware(ctx, u) })
+//	// awslambda is github.com/aws/aws-lambda-go/lambda.
+//	awslambda.Start(lambda.Handler(source))
+func Handler(source *tgbotapp.WebhookSource) func(context.Context, events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	return func(_ context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		var update tgbotapi.Update
+		if err := json.Unmarshal([]byte(req.Body), &update); err != nil {
+			return events.APIGatewayProxyResponse{StatusCode: 400}, fmt.Errorf("decode update: %w", err)
+		}
+
+		if err := source.HandleUpdate(headerLookup(req.Headers, tgbotapp.SecretTokenHeader), update); err != nil {
+			return events.APIGatewayProxyResponse{StatusCode: 401}, err
+		}
+
+		return events.APIGatewayProxyResponse{StatusCode: 200}, nil
+	}
+}
+
+// headerLookup finds key in headers case-insensitively, since API Gateway
+// does not guarantee the casing a proxy request's header keys arrive in.
+func headerLookup(headers map[string]string, key string) string {
+	canonical := textproto.CanonicalMIMEHeaderKey(key)
+
+	for k, v := range headers {
+		if textproto.CanonicalMIMEHeaderKey(k) == canonical {
+			return v
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,106 @@
+package tgbotapp
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// placeholderExpr matches named placeholders such as {id} or {item} inside a
+// pattern passed to AddPatternHandler.
+var placeholderExpr = regexp.MustCompile(`\{(\w+)\}`)
+
+// patternHandler is a single compiled entry in a RouteTable's pattern list.
+type patternHandler struct {
+	raw   string
+	regex *regexp.Regexp
+	info  HandlerInfo
+}
+
+// compilePattern turns a pattern into an anchored regular expression.
+//
+// A pattern may be plain regexp syntax ("order_(\d+)") or use named
+// placeholders ("order/{id}", "cart/{item}/remove"), which are expanded to
+// named capture groups matching a single path segment ([^/]+). The two
+// styles cannot be mixed within the same pattern: any pattern containing "{"
+// is treated as placeholder style and everything outside its placeholders is
+// matched literally, so regexp repetition braces such as \d{3} are not
+// supported.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if !strings.Contains(pattern, "{") {
+		if strings.HasPrefix(pattern, "^") && strings.HasSuffix(pattern, "$") {
+			return regexp.Compile(pattern)
+		}
+
+		return regexp.Compile("^" + pattern + "$")
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+
+	last := 0
+	for _, loc := range placeholderExpr.FindAllStringSubmatchIndex(pattern, -1) {
+		b.WriteString(regexp.QuoteMeta(pattern[last:loc[0]]))
+		b.WriteString(fmt.Sprintf("(?P<%s>[^/]+)", pattern[loc[2]:loc[3]]))
+		last = loc[1]
+	}
+	b.WriteString(regexp.QuoteMeta(pattern[last:]))
+	b.WriteString("$")
+
+	return regexp.Compile(b.String())
+}
+
+// AddPatternHandler implements Router.
+func (r *RouteTable) AddPatternHandler(pattern string, handlerType HandlerAction, f HandlerFunc) error {
+	return r.AddPatternHandlerWithMiddleware(pattern, handlerType, f)
+}
+
+// AddPatternHandlerWithMiddleware implements Router.
+func (r *RouteTable) AddPatternHandlerWithMiddleware(pattern string, handlerType HandlerAction, f HandlerFunc, mws ...Middleware) error {
+	if len(pattern) < 1 {
+		return NewErrInvalidArgument("pattern must not be empty.", "pattern")
+	}
+
+	regex, err := compilePattern(pattern)
+	if err != nil {
+		return NewErrInvalidArgument(fmt.Sprintf("pattern is not a valid regular expression: %s", err), "pattern")
+	}
+
+	r.patterns[handlerType] = append(r.patterns[handlerType], &patternHandler{
+		raw:   pattern,
+		regex: regex,
+		info: HandlerInfo{
+			Name:        pattern,
+			Type:        handlerType,
+			Func:        chainMiddleware(mws, f),
+			Middlewares: mws,
+		},
+	})
+
+	return nil
+}
+
+// MatchPattern implements Router. Patterns are matched in registration
+// order; the first one that matches wins.
+func (r *RouteTable) MatchPattern(name string, handlerType HandlerAction) (*HandlerInfo, map[string]string, bool) {
+	for _, ph := range r.patterns[handlerType] {
+		match := ph.regex.FindStringSubmatch(name)
+		if match == nil {
+			continue
+		}
+
+		named := make(map[string]string)
+		for i, group := range ph.regex.SubexpNames() {
+			if i == 0 || group == "" {
+				continue
+			}
+			named[group] = match[i]
+		}
+
+		info := ph.info
+
+		return &info, named, true
+	}
+
+	return nil, nil, false
+}
@@ -0,0 +1,120 @@
+package tgbotapp
+
+import "testing"
+
+func TestCompilePattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		input   string
+		wantOK  bool
+		named   map[string]string
+	}{
+		{
+			name:    "plain regexp",
+			pattern: `order_(\d+)`,
+			input:   "order_123",
+			wantOK:  true,
+		},
+		{
+			name:    "plain regexp mismatch",
+			pattern: `order_(\d+)`,
+			input:   "order_abc",
+			wantOK:  false,
+		},
+		{
+			name:    "single placeholder",
+			pattern: "order/{id}",
+			input:   "order/123",
+			wantOK:  true,
+			named:   map[string]string{"id": "123"},
+		},
+		{
+			name:    "placeholder followed by literal segment",
+			pattern: "cart/{item}/remove",
+			input:   "cart/42/remove",
+			wantOK:  true,
+			named:   map[string]string{"item": "42"},
+		},
+		{
+			name:    "placeholder does not cross segments",
+			pattern: "order/{id}",
+			input:   "order/123/extra",
+			wantOK:  false,
+		},
+		{
+			name:    "already anchored regexp is left alone",
+			pattern: `^order_(\d+)$`,
+			input:   "order_123",
+			wantOK:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			regex, err := compilePattern(tt.pattern)
+			if err != nil {
+				t.Fatalf("compilePattern(%q) returned error: %v", tt.pattern, err)
+			}
+
+			match := regex.FindStringSubmatch(tt.input)
+			gotOK := match != nil
+			if gotOK != tt.wantOK {
+				t.Fatalf("compilePattern(%q) match against %q = %v, want %v", tt.pattern, tt.input, gotOK, tt.wantOK)
+			}
+
+			for group, want := range tt.named {
+				got := match[regex.SubexpIndex(group)]
+				if got != want {
+					t.Errorf("named group %q = %q, want %q", group, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRouteTableMatchPattern(t *testing.T) {
+	table := NewRouteTable()
+
+	if err := table.AddPatternHandler("order/{id}", CommandHandler, func(*BotContext) {}); err != nil {
+		t.Fatalf("AddPatternHandler returned error: %v", err)
+	}
+	if err := table.AddPatternHandler("cart/{item}/remove", CallbackHandler, func(*BotContext) {}); err != nil {
+		t.Fatalf("AddPatternHandler returned error: %v", err)
+	}
+
+	info, named, ok := table.MatchPattern("order/123", CommandHandler)
+	if !ok {
+		t.Fatal("MatchPattern(\"order/123\") = false, want true")
+	}
+	if info == nil {
+		t.Fatal("MatchPattern returned ok=true with a nil HandlerInfo")
+	}
+	if named["id"] != "123" {
+		t.Errorf("named[\"id\"] = %q, want \"123\"", named["id"])
+	}
+
+	if _, _, ok := table.MatchPattern("order/123", CallbackHandler); ok {
+		t.Error("MatchPattern matched a pattern registered for a different HandlerAction")
+	}
+
+	if _, _, ok := table.MatchPattern("nope", CommandHandler); ok {
+		t.Error("MatchPattern matched an input that fits no registered pattern")
+	}
+}
+
+func TestAddPatternHandlerRejectsEmptyPattern(t *testing.T) {
+	table := NewRouteTable()
+
+	if err := table.AddPatternHandler("", CommandHandler, func(*BotContext) {}); err == nil {
+		t.Fatal("AddPatternHandler(\"\", ...) returned nil error, want one")
+	}
+}
+
+func TestAddPatternHandlerRejectsInvalidRegexp(t *testing.T) {
+	table := NewRouteTable()
+
+	if err := table.AddPatternHandler("order_(", CommandHandler, func(*BotContext) {}); err == nil {
+		t.Fatal("AddPatternHandler with invalid regexp returned nil error, want one")
+	}
+}
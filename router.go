@@ -14,6 +14,17 @@ const (
 	CallbackHandler
 	MessageHandler
 	DocumentHandler
+	InlineQueryHandler
+	ChosenInlineResultHandler
+	ChannelPostHandler
+	EditedMessageHandler
+	EditedChannelPostHandler
+	PollHandler
+	PollAnswerHandler
+	ShippingQueryHandler
+	PreCheckoutQueryHandler
+	MyChatMemberHandler
+	ChatMemberHandler
 )
 
 func (h HandlerAction) String() string {
@@ -26,6 +37,28 @@ func (h HandlerAction) String() string {
 		return "Message State Handler"
 	case DocumentHandler:
 		return "Document Handler"
+	case InlineQueryHandler:
+		return "Inline Query Handler"
+	case ChosenInlineResultHandler:
+		return "Chosen Inline Result Handler"
+	case ChannelPostHandler:
+		return "Channel Post Handler"
+	case EditedMessageHandler:
+		return "Edited Message Handler"
+	case EditedChannelPostHandler:
+		return "Edited Channel Post Handler"
+	case PollHandler:
+		return "Poll Handler"
+	case PollAnswerHandler:
+		return "Poll Answer Handler"
+	case ShippingQueryHandler:
+		return "Shipping Query Handler"
+	case PreCheckoutQueryHandler:
+		return "Pre Checkout Query Handler"
+	case MyChatMemberHandler:
+		return "My Chat Member Handler"
+	case ChatMemberHandler:
+		return "Chat Member Handler"
 	default:
 		return "Unknown Handler"
 	}
@@ -33,17 +66,51 @@ func (h HandlerAction) String() string {
 
 const (
 	CommandDelimiter = "|"
+
+	// SingletonHandlerName is the conventional registration key for handler
+	// types that have no natural name to route on (inline queries, chosen
+	// inline results, polls, etc). Register these with AddHandler(SingletonHandlerName, ...).
+	SingletonHandlerName = ""
 )
 
 type HandlerInfo struct {
 	Name string
 	Type HandlerAction
 	Func HandlerFunc
+
+	// Middlewares is the resolved chain, in run order, that Func was wrapped
+	// with by AddHandlerWithMiddleware or a Group. Func already applies it,
+	// so this is exposed for introspection only.
+	Middlewares []Middleware
 }
 
 type Router interface {
 	GetHandler(name string, handlerType HandlerAction) (*HandlerInfo, bool)
 	AddHandler(name string, handlerType HandlerAction, f HandlerFunc) error
+
+	// AddHandlerWithMiddleware behaves like AddHandler, but wraps f with mws
+	// before registering it, so handler-specific concerns (auth, rate
+	// limiting, analytics) don't have to be re-checked inside f itself.
+	AddHandlerWithMiddleware(name string, handlerType HandlerAction, f HandlerFunc, mws ...Middleware) error
+
+	// Group returns a sub-router whose AddHandler/AddHandlerWithMiddleware/
+	// AddPatternHandler registrations wrap f with mws in addition to
+	// whatever middleware the call itself supplies.
+	Group(name string, mws ...Middleware) Router
+
+	// AddPatternHandler registers f under a regexp or named-placeholder
+	// pattern ("order/{id}", "cart/{item}/remove") for handlerType. Patterns
+	// are only consulted once an exact-name lookup via GetHandler misses.
+	AddPatternHandler(pattern string, handlerType HandlerAction, f HandlerFunc) error
+
+	// AddPatternHandlerWithMiddleware behaves like AddPatternHandler, but
+	// wraps f with mws before registering it.
+	AddPatternHandlerWithMiddleware(pattern string, handlerType HandlerAction, f HandlerFunc, mws ...Middleware) error
+
+	// MatchPattern tries every pattern registered for handlerType, in
+	// registration order, against name. On a match it returns the handler
+	// along with the named placeholder captures.
+	MatchPattern(name string, handlerType HandlerAction) (*HandlerInfo, map[string]string, bool)
 }
 
 func defaultHandler(ctx *BotContext) {
@@ -65,6 +132,123 @@ func RouterWithDefault(router Router, defaultFunc HandlerFunc) Middleware {
 
 		var f HandlerFunc = defaultFunc
 		switch {
+		case context.Update.InlineQuery != nil:
+			h, ok := router.GetHandler(SingletonHandlerName, InlineQueryHandler)
+			if !ok {
+				logger.WarnContext(context.Ctx, "No handler found for inline query.")
+				break
+			}
+
+			f = h.Func
+
+		case context.Update.ChosenInlineResult != nil:
+			h, ok := router.GetHandler(SingletonHandlerName, ChosenInlineResultHandler)
+			if !ok {
+				logger.WarnContext(context.Ctx, "No handler found for chosen inline result.")
+				break
+			}
+
+			f = h.Func
+
+		case context.Update.ShippingQuery != nil:
+			h, ok := router.GetHandler(SingletonHandlerName, ShippingQueryHandler)
+			if !ok {
+				logger.WarnContext(context.Ctx, "No handler found for shipping query.")
+				break
+			}
+
+			f = h.Func
+
+		case context.Update.PreCheckoutQuery != nil:
+			h, ok := router.GetHandler(SingletonHandlerName, PreCheckoutQueryHandler)
+			if !ok {
+				logger.WarnContext(context.Ctx, "No handler found for pre checkout query.")
+				break
+			}
+
+			f = h.Func
+
+		case context.Update.Poll != nil:
+			h, ok := router.GetHandler(SingletonHandlerName, PollHandler)
+			if !ok {
+				logger.WarnContext(context.Ctx, "No handler found for poll.")
+				break
+			}
+
+			f = h.Func
+
+		case context.Update.PollAnswer != nil:
+			h, ok := router.GetHandler(SingletonHandlerName, PollAnswerHandler)
+			if !ok {
+				logger.WarnContext(context.Ctx, "No handler found for poll answer.")
+				break
+			}
+
+			f = h.Func
+
+		case context.Update.MyChatMember != nil:
+			h, ok := router.GetHandler(SingletonHandlerName, MyChatMemberHandler)
+			if !ok {
+				logger.WarnContext(context.Ctx, "No handler found for my chat member update.")
+				break
+			}
+
+			f = h.Func
+
+		case context.Update.ChatMember != nil:
+			h, ok := router.GetHandler(SingletonHandlerName, ChatMemberHandler)
+			if !ok {
+				logger.WarnContext(context.Ctx, "No handler found for chat member update.")
+				break
+			}
+
+			f = h.Func
+
+		case context.Update.ChannelPost != nil:
+			if context.Update.ChannelPost.IsCommand() {
+				command := context.Update.ChannelPost.Command()
+				h, ok := router.GetHandler(command, CommandHandler)
+
+				if !ok {
+					h, context.Named, ok = router.MatchPattern(command, CommandHandler)
+				}
+
+				if !ok {
+					logger.WarnContext(context.Ctx, "No handler found for command.", "commandName", command)
+					break
+				}
+
+				f = h.Func
+				context.Params = strings.Split(context.Update.ChannelPost.CommandArguments(), CommandDelimiter)
+				break
+			}
+
+			h, ok := router.GetHandler(SingletonHandlerName, ChannelPostHandler)
+			if !ok {
+				logger.WarnContext(context.Ctx, "No handler found for channel post.")
+				break
+			}
+
+			f = h.Func
+
+		case context.Update.EditedChannelPost != nil:
+			h, ok := router.GetHandler(SingletonHandlerName, EditedChannelPostHandler)
+			if !ok {
+				logger.WarnContext(context.Ctx, "No handler found for edited channel post.")
+				break
+			}
+
+			f = h.Func
+
+		case context.Update.EditedMessage != nil:
+			h, ok := router.GetHandler(SingletonHandlerName, EditedMessageHandler)
+			if !ok {
+				logger.WarnContext(context.Ctx, "No handler found for edited message.")
+				break
+			}
+
+			f = h.Func
+
 		case context.Update.CallbackQuery != nil:
 			var action string
 			callbackData := context.Update.CallbackQuery.Data
@@ -72,6 +256,23 @@ func RouterWithDefault(router Router, defaultFunc HandlerFunc) Middleware {
 
 			h, ok := router.GetHandler(action, CallbackHandler)
 
+			if !ok {
+				// CallbackData-encoded payloads ("action:base64") are keyed
+				// differently than the CommandDelimiter scheme above, so
+				// they need their own action lookup before falling back to
+				// patterns.
+				if cbAction, hasPayload := callbackDataAction(callbackData); hasPayload {
+					h, ok = router.GetHandler(cbAction, CallbackHandler)
+				}
+			}
+
+			if !ok {
+				// Pattern handlers match against the raw, unsplit callback
+				// data so a captured segment containing CommandDelimiter
+				// is never silently chopped up by extractCallback.
+				h, context.Named, ok = router.MatchPattern(callbackData, CallbackHandler)
+			}
+
 			if !ok {
 				logger.WarnContext(context.Ctx, "No handler found for callback.", "callbackName", callbackData)
 				break
@@ -83,6 +284,10 @@ func RouterWithDefault(router Router, defaultFunc HandlerFunc) Middleware {
 			command := context.Update.Message.Command()
 			h, ok := router.GetHandler(command, CommandHandler)
 
+			if !ok {
+				h, context.Named, ok = router.MatchPattern(command, CommandHandler)
+			}
+
 			if !ok {
 				logger.WarnContext(context.Ctx, "No handler found for command.", "commandName", command)
 				break
@@ -139,30 +344,19 @@ func extractCallback(callbackData string) (action string, args []string) {
 // Default Implementation for Route Table
 type RouteTable struct {
 	handlers map[HandlerAction]map[string]HandlerInfo
+	patterns map[HandlerAction][]*patternHandler
 }
 
 // AddHandler implements Router.
 func (r *RouteTable) AddHandler(name string, handlerType HandlerAction, f HandlerFunc) error {
-	if len(name) < 1 {
-		return NewErrInvalidArgument("name must not be empty.", "name")
-	}
-
-	if _, ok := r.handlers[handlerType]; !ok {
-		r.handlers[handlerType] = make(map[string]HandlerInfo)
-	}
-
-	if _, ok := r.handlers[handlerType][name]; ok {
-		return NewErrHandlerAlreadyExists(name, handlerType)
-	}
-
-	r.handlers[handlerType][name] = HandlerInfo{
-		Name: name,
-		Type: handlerType,
-		Func: f,
-	}
-
-	return nil
+	return r.AddHandlerWithMiddleware(name, handlerType, f)
+}
 
+// rootTable returns r itself, terminating the groupRouter.rootTable
+// delegation chain used by registryFor to scope conversation registries to
+// the underlying RouteTable regardless of how many Group layers reach it.
+func (r *RouteTable) rootTable() *RouteTable {
+	return r
 }
 
 // GetHandler implements Router.
@@ -180,6 +374,7 @@ func (r *RouteTable) GetHandler(name string, handlerType HandlerAction) (*Handle
 func NewRouteTable() Router {
 	return &RouteTable{
 		handlers: make(map[HandlerAction]map[string]HandlerInfo),
+		patterns: make(map[HandlerAction][]*patternHandler),
 	}
 }
 
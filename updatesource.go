@@ -0,0 +1,165 @@
+package tgbotapp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// SecretTokenHeader is the header Telegram sets on every webhook request
+// when a secret token was configured via SetWebhook.
+const SecretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// UpdateSource delivers tgbotapi.Update values to handle until ctx is
+// cancelled. Both LongPollSource and WebhookSource implement it, so the
+// Router/Middleware stack behind handle runs identically regardless of how
+// updates actually arrive.
+type UpdateSource interface {
+	Listen(ctx context.Context, handle func(tgbotapi.Update)) error
+}
+
+// LongPollSource drives handle from the Bot API's long-poll getUpdates loop.
+type LongPollSource struct {
+	Bot    *tgbotapi.BotAPI
+	Config tgbotapi.UpdateConfig
+}
+
+// NewLongPollSource builds a LongPollSource that polls bot using config.
+func NewLongPollSource(bot *tgbotapi.BotAPI, config tgbotapi.UpdateConfig) *LongPollSource {
+	return &LongPollSource{
+		Bot:    bot,
+		Config: config,
+	}
+}
+
+// Listen implements UpdateSource.
+func (s *LongPollSource) Listen(ctx context.Context, handle func(tgbotapi.Update)) error {
+	updates := s.Bot.GetUpdatesChan(s.Config)
+	defer s.Bot.StopReceivingUpdates()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case update := <-updates:
+			handle(update)
+		}
+	}
+}
+
+// WebhookSource drives handle from updates Telegram pushes to an HTTP
+// webhook. It implements http.Handler directly, and exposes HandleUpdate so
+// non-net/http transports (an AWS Lambda proxy integration, for example) can
+// feed it updates they have already decoded themselves.
+//
+//	source := tgbotapp.NewWebhookSource("my-secret")
+//	go source.Listen(ctx, func(u tgbotapi.Update) { middleware(ctx, u) })
+//
+//	mux := http.NewServeMux()
+//	mux.Handle("/telegram/webhook", source)
+//	http.ListenAndServe(":8080", mux)
+type WebhookSource struct {
+	// SecretToken, when non-empty, must match the X-Telegram-Bot-Api-Secret-Token
+	// header of every incoming request.
+	SecretToken string
+
+	mu     sync.RWMutex
+	handle func(tgbotapi.Update)
+}
+
+// NewWebhookSource builds a WebhookSource that requires secretToken on every
+// request. Pass an empty string to skip secret validation.
+func NewWebhookSource(secretToken string) *WebhookSource {
+	return &WebhookSource{SecretToken: secretToken}
+}
+
+// Listen implements UpdateSource. It registers handle and blocks until ctx
+// is done; updates are actually delivered from ServeHTTP/HandleUpdate as
+// requests arrive. Until Listen has run, ServeHTTP/HandleUpdate report an
+// error rather than silently dropping updates.
+func (s *WebhookSource) Listen(ctx context.Context, handle func(tgbotapi.Update)) error {
+	s.mu.Lock()
+	s.handle = handle
+	s.mu.Unlock()
+
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// ServeHTTP implements http.Handler.
+func (s *WebhookSource) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	if s.SecretToken != "" && r.Header.Get(SecretTokenHeader) != s.SecretToken {
+		http.Error(w, "secret token mismatch", http.StatusUnauthorized)
+		return
+	}
+
+	var update tgbotapi.Update
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		http.Error(w, "invalid update payload", http.StatusBadRequest)
+		return
+	}
+
+	// A 5xx here (rather than acknowledging with 200) tells Telegram to
+	// retry the delivery instead of considering the update handled.
+	if err := s.deliver(update); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// HandleUpdate validates secretToken and delivers update to the handler
+// passed to Listen. Use this from transports that already decode the
+// request body themselves, such as an AWS Lambda proxy integration.
+func (s *WebhookSource) HandleUpdate(secretToken string, update tgbotapi.Update) error {
+	if s.SecretToken != "" && secretToken != s.SecretToken {
+		return NewErrWebhookSecretMismatch()
+	}
+
+	return s.deliver(update)
+}
+
+// deliver hands update to the handler registered via Listen. It errors
+// instead of silently dropping the update when Listen hasn't registered a
+// handler yet, so callers have a chance to surface a retryable failure
+// rather than acknowledging an update nobody received.
+func (s *WebhookSource) deliver(update tgbotapi.Update) error {
+	s.mu.RLock()
+	handle := s.handle
+	s.mu.RUnlock()
+
+	if handle == nil {
+		return NewErrWebhookNotReady()
+	}
+
+	handle(update)
+	return nil
+}
+
+// SetWebhook points the Bot API at url, scoped to secret and, when given,
+// restricted to allowedUpdates.
+func SetWebhook(bot *tgbotapi.BotAPI, url, secret string, allowedUpdates ...string) error {
+	cfg, err := tgbotapi.NewWebhook(url)
+	if err != nil {
+		return err
+	}
+
+	cfg.SecretToken = secret
+	cfg.AllowedUpdates = allowedUpdates
+
+	_, err = bot.Request(cfg)
+	return err
+}
+
+// DeleteWebhook removes any webhook currently configured for bot, falling
+// back to long polling.
+func DeleteWebhook(bot *tgbotapi.BotAPI) error {
+	_, err := bot.Request(tgbotapi.DeleteWebhookConfig{})
+	return err
+}